@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings that control how gptask executes a task.
+type Config struct {
+	APIKey   string `yaml:"-"`
+	Model    string `yaml:"model"`
+	WorkDir  string `yaml:"workdir"`
+	MaxSteps int    `yaml:"max_steps"`
+
+	// Backend selects the session.Provider implementation used to get
+	// completions (e.g. "openai", "anthropic", "ollama"). Defaults to
+	// "openai" when empty.
+	Backend string `yaml:"backend"`
+
+	// Endpoint is the base URL of the backend, used by providers that
+	// talk to a locally or self hosted model server.
+	Endpoint string `yaml:"endpoint"`
+
+	// Temperature controls the randomness of completions. Defaults to
+	// 0.0 (deterministic) when unset.
+	Temperature float32 `yaml:"temperature"`
+
+	// Stop lists the sequences that make a provider stop generating a
+	// completion. Defaults to []string{"observation:"} when empty, so
+	// the model doesn't go on to fabricate its own observation.
+	Stop []string `yaml:"stop"`
+
+	// Interactive pauses before risky actions (shell, file) and lets
+	// the user approve, skip, or edit them before they run.
+	Interactive bool `yaml:"-"`
+
+	// PolicyPath is the path to a YAML sandbox.Policy file constraining
+	// the shell, python and file actions. No restrictions apply when
+	// it's empty.
+	PolicyPath string `yaml:"-"`
+
+	// ToolServers lists external tool server commands (e.g. "git-tool
+	// --verbose") to spawn and register alongside the built-in tools.
+	ToolServers []string `yaml:"tool_servers"`
+
+	// SearchBackend selects the search.Provider implementation used by
+	// the search action (e.g. "duckduckgo", "searxng", "bing",
+	// "google", "brave"). Defaults to "duckduckgo" when empty.
+	SearchBackend string `yaml:"search_backend"`
+
+	// SearchAPIKey authenticates with the selected search backend.
+	SearchAPIKey string `yaml:"-"`
+
+	// SearchEndpoint is the base URL of a self hosted search backend
+	// (currently only used by searxng).
+	SearchEndpoint string `yaml:"search_endpoint"`
+
+	// SearchCX is the Google Programmable Search engine ID, required by
+	// the google search backend.
+	SearchCX string `yaml:"search_cx"`
+
+	// CacheDir is where search results are cached on disk. Defaults to
+	// "$WorkDir/.gptask/search-cache" when empty.
+	CacheDir string `yaml:"cache_dir"`
+
+	// CacheTTL is how long a cached search result stays valid,
+	// formatted as a time.ParseDuration string (e.g. "1h"). Defaults to
+	// 1 hour when empty.
+	CacheTTL string `yaml:"cache_ttl"`
+
+	// FetchPage makes the search action also fetch and return the
+	// extracted text of the top results' pages, instead of only their
+	// snippets.
+	FetchPage bool `yaml:"-"`
+
+	// MaxContextTokens is the target model's context window, in tokens.
+	// When set, the conversation is compacted once it grows past
+	// CompactThreshold of this budget. Compaction is disabled when
+	// this is zero.
+	MaxContextTokens int `yaml:"max_context_tokens"`
+
+	// CompactThreshold is the fraction of MaxContextTokens that
+	// triggers compaction. Defaults to 0.8 when empty.
+	CompactThreshold float64 `yaml:"compact_threshold"`
+}
+
+// Load reads a YAML config file from path and merges its values into c.
+// It is not an error for path to not exist; callers only pay for a
+// config file when they ask for one with --config.
+func Load(path string, c *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return yaml.Unmarshal(data, c)
+}
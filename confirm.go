@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/summerwind/gptask/log"
+)
+
+// stepDecision is the user's response to confirmStep's prompt.
+type stepDecision int
+
+const (
+	decisionRun stepDecision = iota
+	decisionSkip
+	decisionRewind
+)
+
+// confirmStep shows the model's proposed step to the user and lets them
+// approve it, skip it, edit its input in $EDITOR, or rewind the session
+// to an earlier step and let the model re-prompt from there. rewindSteps
+// is only meaningful when the returned decision is decisionRewind.
+func confirmStep(s *Step) (decision stepDecision, rewindSteps int, err error) {
+	log.Comment(fmt.Sprintf("About to run action %q:", s.Action))
+	log.CodeBlock(s.Input)
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Run this step? [y]es/[n]o/[e]dit/[r]ewind: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return decisionSkip, 0, err
+		}
+
+		switch strings.TrimSpace(strings.ToLower(line)) {
+		case "y", "":
+			return decisionRun, 0, nil
+		case "n":
+			return decisionSkip, 0, nil
+		case "e":
+			input, err := editInput(s.Input)
+			if err != nil {
+				return decisionSkip, 0, err
+			}
+			s.Input = input
+			log.CodeBlock(s.Input)
+		case "r":
+			n, err := readRewindSteps(reader)
+			if err != nil {
+				return decisionSkip, 0, err
+			}
+			return decisionRewind, n, nil
+		default:
+			fmt.Println("please answer y, n, e or r")
+		}
+	}
+}
+
+// readRewindSteps prompts for how many completed steps to rewind,
+// defaulting to 1.
+func readRewindSteps(reader *bufio.Reader) (int, error) {
+	fmt.Print("Rewind how many steps? [1]: ")
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return 1, nil
+	}
+
+	n, err := strconv.Atoi(line)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid step count %q", line)
+	}
+
+	return n, nil
+}
+
+// editInput opens input in $EDITOR (vi by default) and returns the
+// edited content.
+func editInput(input string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "gptask-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(input); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(editor, f.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\n"), nil
+}
@@ -3,12 +3,95 @@ package log
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/logrusorgru/aurora/v4"
 )
 
 var EnableDebug bool
 
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Spinner renders an animated progress indicator on the current line
+// while a long-running operation (e.g. a streaming completion) is in
+// progress.
+type Spinner struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSpinner starts a spinner that prints label followed by an
+// animated frame until Stop is called.
+func NewSpinner(label string) *Spinner {
+	s := &Spinner{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		i := 0
+		for {
+			select {
+			case <-s.stop:
+				fmt.Printf("\r%s\r", strings.Repeat(" ", len(label)+2))
+				return
+			case <-ticker.C:
+				fmt.Printf("\r%s %s", label, spinnerFrames[i%len(spinnerFrames)])
+				i++
+			}
+		}
+	}()
+
+	return s
+}
+
+// Stop halts the spinner animation and clears its line. It is safe to
+// call more than once.
+func (s *Spinner) Stop() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+}
+
+// Progress renders a determinate "label [n/total]" indicator on the
+// current line for an operation with a known number of steps, such as a
+// multi-command shell action.
+type Progress struct {
+	label string
+	total int
+}
+
+// NewProgress starts a progress indicator for an operation with total
+// steps, immediately rendering step 0.
+func NewProgress(label string, total int) *Progress {
+	p := &Progress{label: label, total: total}
+	p.draw(0)
+	return p
+}
+
+// Step advances the indicator to step n out of its total.
+func (p *Progress) Step(n int) {
+	p.draw(n)
+}
+
+// Stop clears the indicator's line. It is safe to call more than once.
+func (p *Progress) Stop() {
+	fmt.Printf("\r%s\r", strings.Repeat(" ", len(p.label)+20))
+}
+
+func (p *Progress) draw(n int) {
+	fmt.Printf("\r%s [%d/%d]", p.label, n, p.total)
+}
+
 func Comment(comment string) {
 	fmt.Println(aurora.Gray(12, fmt.Sprintf("# %s", comment)))
 }
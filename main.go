@@ -1,7 +1,6 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"os"
 
@@ -18,7 +17,8 @@ var (
 
 func main() {
 	var (
-		c config.Config
+		c          config.Config
+		configPath string
 	)
 
 	var cmd = &cobra.Command{
@@ -27,6 +27,11 @@ func main() {
 		Args:    cobra.ExactArgs(1),
 		Version: fmt.Sprintf("%s (%s)", _version, _commit),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if configPath != "" {
+				if err := config.Load(configPath, &c); err != nil {
+					return err
+				}
+			}
 			return run(&c, args[0])
 		},
 	}
@@ -35,6 +40,19 @@ func main() {
 	pflag.StringVarP(&c.Model, "model", "m", "gpt-3.5-turbo-0301", "Name of the GPT model to use")
 	pflag.StringVarP(&c.WorkDir, "workdir", "w", "/root", "Working directory")
 	pflag.IntVar(&c.MaxSteps, "max-steps", 10, "Maximum number of steps the task is allowed to take")
+	pflag.StringVar(&c.Backend, "backend", "openai", "LLM backend to use (openai, anthropic, google, ollama, grpc)")
+	pflag.StringVar(&c.Endpoint, "endpoint", "", "Endpoint URL for the selected backend")
+	pflag.Float32Var(&c.Temperature, "temperature", 0.0, "Sampling temperature for completions")
+	pflag.StringSliceVar(&c.Stop, "stop", []string{"observation:"}, "Stop sequences that end a completion")
+	pflag.StringVar(&configPath, "config", "", "Path to a YAML config file")
+	pflag.BoolVarP(&c.Interactive, "interactive", "i", false, "Pause for confirmation before running shell or file actions")
+	pflag.StringVar(&c.PolicyPath, "policy", "", "Path to a YAML sandbox policy file")
+	pflag.StringVar(&c.SearchBackend, "search-backend", "duckduckgo", "Search backend to use (duckduckgo, searxng, bing, google, brave)")
+	pflag.StringVar(&c.SearchEndpoint, "search-endpoint", "", "Endpoint URL for the selected search backend")
+	pflag.StringVar(&c.SearchCX, "search-cx", "", "Google Programmable Search engine ID")
+	pflag.BoolVar(&c.FetchPage, "fetch-page", false, "Fetch and read the top search result pages instead of only their snippets")
+	pflag.IntVar(&c.MaxContextTokens, "max-context-tokens", 0, "Model's context window in tokens; compacts the conversation as it fills up (0 disables compaction)")
+	pflag.Float64Var(&c.CompactThreshold, "compact-threshold", 0.8, "Fraction of max-context-tokens that triggers compaction")
 	pflag.BoolVar(&log.EnableDebug, "debug", false, "Write debug log")
 
 	cmd.PersistentFlags().Bool("help", false, "Display this help and exit")
@@ -47,12 +65,28 @@ func main() {
 }
 
 func run(c *config.Config, task string) error {
-	c.APIKey = os.Getenv("OPENAI_API_KEY")
-	if c.APIKey == "" {
-		return errors.New("Enrironment variable 'OPENAI_API_KEY' must be set")
+	switch c.Backend {
+	case "", "openai":
+		c.APIKey = os.Getenv("OPENAI_API_KEY")
+	case "anthropic":
+		c.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	case "google":
+		c.APIKey = os.Getenv("GOOGLE_API_KEY")
 	}
 
-	runner := NewRunner(c)
+	switch c.SearchBackend {
+	case "bing":
+		c.SearchAPIKey = os.Getenv("BING_API_KEY")
+	case "google":
+		c.SearchAPIKey = os.Getenv("GOOGLE_API_KEY")
+	case "brave":
+		c.SearchAPIKey = os.Getenv("BRAVE_API_KEY")
+	}
+
+	runner, err := NewRunner(c)
+	if err != nil {
+		return err
+	}
 
 	return runner.Run(task)
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/summerwind/gptask/tool"
+)
+
+const systemPromptPreamble = "" +
+	"You are an autonomous agent that completes tasks on a Linux machine by\n" +
+	"taking a sequence of steps. At each step you must respond with exactly\n" +
+	"one step in the following format:\n" +
+	"\n" +
+	"thought: <your reasoning about what to do next>\n" +
+	"action: <one of the available actions below>\n" +
+	"input:\n" +
+	"```\n" +
+	"<the input for the action>\n" +
+	"```\n" +
+	"\n" +
+	"The available actions are:\n"
+
+const systemPromptClosing = "" +
+	"- done: the task is complete. No input is required.\n" +
+	"\n" +
+	"After an action runs, you will receive its result as:\n" +
+	"\n" +
+	"observation:\n" +
+	"```\n" +
+	"<the result of the action>\n" +
+	"```\n" +
+	"\n" +
+	"Use the observation to decide your next step. Keep taking steps until\n" +
+	"the task is complete, then respond with action \"done\".\n"
+
+// buildSystemPrompt renders the system prompt from the registry's tool
+// schemas, so the model is only ever told about actions that are
+// actually wired up.
+func buildSystemPrompt(registry *tool.Registry) string {
+	var b strings.Builder
+
+	b.WriteString(systemPromptPreamble)
+	for _, s := range registry.Schemas() {
+		fmt.Fprintf(&b, "- %s: %s input must be %s.\n", s.Name, s.Description, s.Input)
+	}
+	b.WriteString(systemPromptClosing)
+
+	return b.String()
+}
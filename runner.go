@@ -2,27 +2,33 @@ package main
 
 import (
 	"context"
-	_ "embed"
 	"errors"
 	"fmt"
-	"net/http"
-	"net/url"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
-
-	"github.com/PuerkitoBio/goquery"
-	"gopkg.in/yaml.v3"
+	"time"
 
 	"github.com/summerwind/gptask/config"
 	"github.com/summerwind/gptask/log"
+	"github.com/summerwind/gptask/sandbox"
+	"github.com/summerwind/gptask/search"
 	"github.com/summerwind/gptask/session"
 	"github.com/summerwind/gptask/shell"
+	"github.com/summerwind/gptask/tool"
 )
 
-//go:embed prompt.txt
-var systemPrompt string
+// defaultCacheTTL is how long a cached search result stays valid when
+// Config.CacheTTL isn't set.
+const defaultCacheTTL = 1 * time.Hour
+
+// maxCacheEntries bounds how many search results are kept on disk
+// before the oldest are evicted.
+const maxCacheEntries = 1000
+
+// defaultCompactThreshold is the fraction of Config.MaxContextTokens
+// that triggers conversation compaction when Config.CompactThreshold
+// isn't set.
+const defaultCompactThreshold = 0.8
 
 var (
 	errInvalidFormat = errors.New("invalid format")
@@ -43,29 +49,104 @@ type SearchActionInput struct {
 	Query string `json:"query"`
 }
 
-type SearchActionQueryResult struct {
-	Title string
-	Desc  string
-	URL   string
-}
-
 type Runner struct {
-	config  *config.Config
-	session *session.Session
-	shell   *shell.Shell
+	config    *config.Config
+	session   *session.Session
+	shell     *shell.Shell
+	policy    *sandbox.Policy
+	registry  *tool.Registry
+	compactor *session.Compactor
 }
 
-func NewRunner(c *config.Config) *Runner {
-	return &Runner{
-		config:  c,
-		session: session.New(c, systemPrompt),
-		shell:   shell.New(),
+func NewRunner(c *config.Config) (*Runner, error) {
+	var policy *sandbox.Policy
+	if c.PolicyPath != "" {
+		p, err := sandbox.Load(c.PolicyPath)
+		if err != nil {
+			return nil, err
+		}
+		policy = p
+	}
+
+	var sh *shell.Shell
+	if policy != nil {
+		name, args := policy.Wrap("bash", []string{"-o", "pipefail", "-s"}, c.WorkDir)
+		sh = shell.NewWithCommand(c.WorkDir, name, args...)
+	} else {
+		sh = shell.New(c.WorkDir)
+	}
+
+	searchProvider, err := search.NewProvider(c)
+	if err != nil {
+		return nil, err
 	}
+
+	cacheDir := c.CacheDir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(c.WorkDir, ".gptask", "search-cache")
+	}
+
+	cacheTTL := defaultCacheTTL
+	if c.CacheTTL != "" {
+		ttl, err := time.ParseDuration(c.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache_ttl: %w", err)
+		}
+		cacheTTL = ttl
+	}
+
+	registry := tool.NewRegistry()
+	registry.Register(&fileTool{shell: sh, policy: policy})
+	registry.Register(&pythonTool{shell: sh, policy: policy})
+	registry.Register(&shellTool{shell: sh, policy: policy})
+	registry.Register(&searchTool{
+		provider:    searchProvider,
+		backendName: c.SearchBackend,
+		cache:       search.NewCache(cacheDir, cacheTTL, maxCacheEntries),
+		fetchPage:   c.FetchPage,
+	})
+
+	for _, spec := range c.ToolServers {
+		parts := strings.Fields(spec)
+		if len(parts) == 0 {
+			continue
+		}
+
+		ext, err := tool.NewExternal(parts[0], parts[1:]...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start tool server %q: %w", spec, err)
+		}
+		registry.Register(ext)
+	}
+
+	s, err := session.New(c, buildSystemPrompt(registry))
+	if err != nil {
+		return nil, err
+	}
+
+	compactThreshold := c.CompactThreshold
+	if compactThreshold == 0 {
+		compactThreshold = defaultCompactThreshold
+	}
+
+	return &Runner{
+		config:    c,
+		session:   s,
+		shell:     sh,
+		policy:    policy,
+		registry:  registry,
+		compactor: session.NewCompactor(c.MaxContextTokens, compactThreshold),
+	}, nil
 }
 
-func (r *Runner) Run(task string) error {
-	var done bool
+// riskyActions are the actions that mutate machine state and are paused
+// for confirmation when the runner is in interactive mode.
+var riskyActions = map[string]bool{
+	"shell": true,
+	"file":  true,
+}
 
+func (r *Runner) Run(task string) error {
 	ctx := context.Background()
 	numStep := 1
 
@@ -78,48 +159,49 @@ func (r *Runner) Run(task string) error {
 	r.session.AddUserMessage(task)
 
 	for {
-		var (
-			obs string
-			err error
-		)
-
-		reply, err := r.session.GetCompletion(ctx)
+		s, err := r.Step(ctx, numStep)
 		if err != nil {
 			return err
 		}
-		log.Debug("reply", reply)
 
-		if reply == "" {
-			log.Debug("retry", "empty reply")
-			continue
+		if s.Action == "done" {
+			log.Comment("Done")
+			return nil
 		}
 
-		s, err := decodeStep(reply)
-		if err != nil {
-			log.Debug("retry", err.Error())
-			continue
-		}
+		if r.config.Interactive && riskyActions[s.Action] {
+			decision, rewindSteps, err := confirmStep(s)
+			if err != nil {
+				return err
+			}
 
-		log.Comment(fmt.Sprintf("Step %d: %s", numStep, s.Thought))
+			switch decision {
+			case decisionSkip:
+				log.Comment("Skipped by user")
 
-		if s.Action == "done" {
-			done = true
-			break
-		}
+				r.session.AddAssistantMessage(encodeStep(s))
+				r.session.AddUserMessage(encodeStep(&Step{Observation: "user declined to run this action"}))
 
-		switch s.Action {
-		case "file":
-			obs, err = r.runFileAction(s)
-		case "python":
-			obs, err = r.runPythonAction(s)
-		case "shell":
-			obs, err = r.runShellAction(s)
-		case "search":
-			obs, err = r.runSearchAction(s)
-		default:
-			err = errInvalidAction
+				numStep += 1
+				if numStep > r.config.MaxSteps {
+					log.Comment("The maximum number of steps has been reached")
+					return nil
+				}
+				continue
+			case decisionRewind:
+				rewound, err := r.rewind(rewindSteps)
+				if err != nil {
+					log.Stderr(err.Error())
+					continue
+				}
+
+				log.Comment(fmt.Sprintf("Rewound %d step(s); re-prompting", rewound))
+				numStep -= rewound
+				continue
+			}
 		}
 
+		obs, err := r.Dispatch(ctx, s)
 		if err != nil {
 			if errors.Is(err, errInvalidAction) {
 				log.Debug("retry", err.Error())
@@ -133,192 +215,127 @@ func (r *Runner) Run(task string) error {
 		r.session.AddAssistantMessage(encodeStep(s))
 		r.session.AddUserMessage(encodeStep(&Step{Observation: obs}))
 
+		compacted, err := r.compactor.Compact(ctx, r.session)
+		if err != nil {
+			log.Debug("compact", err.Error())
+		} else if compacted {
+			log.Debug("compact", "summarized earlier steps to stay within the context budget")
+		}
+
 		numStep += 1
 		if numStep > r.config.MaxSteps {
-			break
+			log.Comment("The maximum number of steps has been reached")
+			return nil
 		}
 	}
-
-	if done {
-		log.Comment("Done")
-	} else {
-		log.Comment("The maximum number of steps has been reached")
-	}
-
-	return nil
 }
 
-func (r *Runner) runFileAction(s *Step) (string, error) {
-	var (
-		input  FileActionInput
-		output string
-	)
-
-	err := yaml.Unmarshal([]byte(s.Input), &input)
-	if err != nil {
-		return "", err
-	}
-
-	log.Command(fmt.Sprintf("vim %s", input.Path))
-
-	if input.Path == "" {
-		output = "file path must be specified"
-		log.Stderr(output)
-		return output, nil
-	}
-
-	if !filepath.IsAbs(input.Path) {
-		input.Path = filepath.Join(r.shell.WorkDir(), input.Path)
-	}
-
-	dirPath := filepath.Dir(input.Path)
-	err = os.MkdirAll(dirPath, 0755)
-	if err != nil {
-		return "", err
-	}
+// Step asks the model for the next proposed step, streaming the reply
+// as it arrives and rendering the thought as soon as it's known. It
+// retries internally on empty or malformed replies.
+func (r *Runner) Step(ctx context.Context, numStep int) (*Step, error) {
+	for {
+		s, err := r.streamStep(ctx, numStep)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			continue
+		}
 
-	err = os.WriteFile(input.Path, []byte(input.Content), 0644)
-	if err != nil {
-		return "", err
+		return s, nil
 	}
-
-	log.CodeBlock(input.Content)
-
-	return ObservationSuccess, nil
 }
 
-func (r *Runner) runPythonAction(s *Step) (string, error) {
-	log.Command("python3")
-	log.CodeBlock(s.Input)
-
-	python := exec.Command("python3", "-c", s.Input)
-	python.Dir = r.shell.WorkDir()
-
-	output, err := python.CombinedOutput()
-	if err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			log.Stderr(string(output))
-			return string(output), nil
-
+// streamStep consumes a single streamed reply, showing a spinner until
+// the model's thought is known and then flipping to rendering it
+// directly. It returns a nil Step (and nil error) when the reply should
+// be retried.
+func (r *Runner) streamStep(ctx context.Context, numStep int) (*Step, error) {
+	chunks, errs := r.session.GetCompletionStream(ctx)
+
+	spinner := log.NewSpinner("thinking")
+	stopped := false
+	stop := func() {
+		if !stopped {
+			spinner.Stop()
+			stopped = true
 		}
-		return "", err
-	}
-
-	outputStr := strings.TrimRight(string(output), "\n")
-	if outputStr == "" {
-		outputStr = ObservationSuccessWithNoOutput
 	}
+	defer stop()
 
-	log.Stdout(outputStr)
-
-	return outputStr, nil
-}
-
-func (r *Runner) runShellAction(s *Step) (string, error) {
-	var (
-		rc     int
-		stdout string
-		stderr string
-		err    error
-	)
+	decoder := newStepDecoder()
+	announced := false
+	var reply strings.Builder
 
-	commands := strings.Split(s.Input, "\n")
-	for _, cmd := range commands {
-		log.Command(cmd)
-
-		rc, stdout, stderr, err = r.shell.Run(cmd)
-		if err != nil {
-			return "", err
+	for chunks != nil {
+		delta, ok := <-chunks
+		if !ok {
+			chunks = nil
+			continue
 		}
-
-		if rc != 0 {
-			if len(stderr) == 0 {
-				return fmt.Sprintf("failed (exit code: %d)", rc), nil
+		reply.WriteString(delta)
+
+		if !announced {
+			// The thought line is only complete once the action line
+			// has started.
+			if partial := decoder.feed(delta); partial.Thought != "" && partial.Action != "" {
+				stop()
+				log.Comment(fmt.Sprintf("Step %d: %s", numStep, partial.Thought))
+				announced = true
 			}
-			return stderr, nil
 		}
 	}
 
-	if len(stdout) == 0 {
-		return ObservationSuccessWithNoOutput, nil
+	if err, ok := <-errs; ok && err != nil {
+		return nil, err
 	}
 
-	return stdout, nil
-}
-
-func (r *Runner) runSearchAction(s *Step) (string, error) {
-	var output string
-
-	log.Command(fmt.Sprintf("search %s", s.Input))
+	text := strings.TrimRight(reply.String(), "\n")
+	log.Debug("reply", text)
 
-	if s.Input == "" {
-		output = "query must be specified"
-		log.Stderr(output)
-		return output, nil
+	if text == "" {
+		log.Debug("retry", "empty reply")
+		return nil, nil
 	}
 
-	payload := url.Values{}
-	payload.Add("q", s.Input)
-	payload.Add("kl", "")
-	payload.Add("df", "")
-
-	req, err := http.NewRequest("POST", "https://lite.duckduckgo.com/lite/", strings.NewReader(payload.Encode()))
+	s, err := decodeStep(text)
 	if err != nil {
-		return "", err
+		log.Debug("retry", err.Error())
+		return nil, nil
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Origin", "https://lite.duckduckgo.com")
-	req.Header.Set("User-Agent", "gptask")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	if res.StatusCode != 200 {
-		return "", fmt.Errorf("failed to get search result (status: %d)", res.StatusCode)
-	}
-
-	defer res.Body.Close()
+	return s, nil
+}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
-	if err != nil {
-		return "", err
+// rewind drops the last n completed step pairs from the session by
+// forking it n*2 messages back, so the next call to Step re-prompts the
+// model from that earlier point. The pinned messages (session.Pinned)
+// are never dropped.
+func (r *Runner) rewind(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("nothing to rewind")
 	}
 
-	results := []SearchActionQueryResult{}
-	doc.Find("body > form > div > table:nth-child(7) > tbody > tr > td").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if len(text) == 0 {
-			return
-		}
-
-		index := i / 8
-		if len(results) <= index {
-			results = append(results, SearchActionQueryResult{})
-		}
+	completed := (len(r.session.Messages) - session.Pinned) / 2
+	if n > completed {
+		return 0, fmt.Errorf("cannot rewind %d step(s): only %d completed", n, completed)
+	}
 
-		switch {
-		case i%8 == 1:
-			results[index].Title = text
-			results[index].URL, _ = s.Find("a").Attr("href")
-		case i%8 == 3:
-			results[index].Desc = text
-		}
-	})
+	r.session = r.session.Fork(len(r.session.Messages) - n*2)
 
-	if len(results) > 3 {
-		results = results[:3]
-	}
+	return n, nil
+}
 
-	lines := []string{}
-	for i := range results {
-		lines = append(lines, fmt.Sprintf("%d. %s: %s", i+1, results[i].Title, results[i].Desc))
+// Dispatch runs the action proposed by s through the matching
+// registered tool and returns the resulting observation. It returns
+// errInvalidAction, unwrapped, for callers that want to retry on an
+// unrecognized action.
+func (r *Runner) Dispatch(ctx context.Context, s *Step) (string, error) {
+	t, ok := r.registry.Get(s.Action)
+	if !ok {
+		return "", errInvalidAction
 	}
-	output = strings.Join(lines, "\n")
-
-	log.CodeBlock(output)
 
-	return output, nil
+	return t.Run(ctx, s.Input)
 }
@@ -0,0 +1,237 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Isolation selects how shell and python actions are executed.
+type Isolation string
+
+const (
+	IsolationNone   Isolation = "none"
+	IsolationChroot Isolation = "chroot"
+	IsolationDocker Isolation = "docker"
+)
+
+// Policy constrains what the runner's shell, python and file actions
+// are allowed to do.
+type Policy struct {
+	ShellAllow []string  `yaml:"shell_allow"`
+	ShellDeny  []string  `yaml:"shell_deny"`
+	WriteRoots []string  `yaml:"write_roots"`
+	Isolation  Isolation `yaml:"isolation"`
+
+	// ChrootDir is the root filesystem shell and python actions are
+	// confined to when Isolation is "chroot". Defaults to the runner's
+	// configured work directory when empty.
+	ChrootDir string `yaml:"chroot_dir"`
+
+	// DockerImage is the image shell and python actions run in when
+	// Isolation is "docker". Defaults to "alpine" when empty.
+	DockerImage string `yaml:"docker_image"`
+
+	// NetworkAllow is a list of regex patterns matched against hosts
+	// found in shell/python actions. A host is allowed if it matches
+	// any pattern here, regardless of NetworkDeny.
+	NetworkAllow []string `yaml:"network_allow"`
+
+	// NetworkDeny blocks network egress to hosts not matched by
+	// NetworkAllow. Under "docker" isolation this is enforced by
+	// disabling the container's network entirely (see Wrap); under
+	// "none" or "chroot" isolation there's no network namespace to
+	// block, so it's enforced only on a best-effort basis by scanning
+	// commands for URLs.
+	NetworkDeny bool `yaml:"network_deny"`
+
+	shellAllow   []*regexp.Regexp
+	shellDeny    []*regexp.Regexp
+	networkAllow []*regexp.Regexp
+}
+
+// Load reads a Policy from a YAML file at path.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (p *Policy) compile() error {
+	for _, pattern := range p.ShellAllow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid shell_allow pattern %q: %w", pattern, err)
+		}
+		p.shellAllow = append(p.shellAllow, re)
+	}
+
+	for _, pattern := range p.ShellDeny {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid shell_deny pattern %q: %w", pattern, err)
+		}
+		p.shellDeny = append(p.shellDeny, re)
+	}
+
+	for _, pattern := range p.NetworkAllow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid network_allow pattern %q: %w", pattern, err)
+		}
+		p.networkAllow = append(p.networkAllow, re)
+	}
+
+	return nil
+}
+
+func (p *Policy) validate() error {
+	switch p.Isolation {
+	case "", IsolationNone, IsolationChroot, IsolationDocker:
+		return nil
+	default:
+		return fmt.Errorf("sandbox: unknown isolation mode %q", p.Isolation)
+	}
+}
+
+// CheckShell returns an error describing why cmd is denied, or nil if
+// it's allowed to run.
+func (p *Policy) CheckShell(cmd string) error {
+	for _, re := range p.shellDeny {
+		if re.MatchString(cmd) {
+			return fmt.Errorf("command denied by policy: %s", cmd)
+		}
+	}
+
+	if len(p.shellAllow) == 0 {
+		return nil
+	}
+
+	for _, re := range p.shellAllow {
+		if re.MatchString(cmd) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("command not in allow list: %s", cmd)
+}
+
+// networkHostPattern extracts the host from a URL appearing in a shell
+// command or python script, for CheckNetwork's best-effort scan.
+var networkHostPattern = regexp.MustCompile(`https?://([^/\s'"]+)`)
+
+// CheckNetwork returns an error describing why cmd is denied network
+// egress, or nil if it's allowed. It's a no-op unless NetworkDeny is
+// set, and only scans for bare URLs, so it should be treated as
+// defense-in-depth rather than a hard guarantee outside of "docker"
+// isolation, where the container's network is also disabled (see Wrap).
+func (p *Policy) CheckNetwork(cmd string) error {
+	if !p.NetworkDeny {
+		return nil
+	}
+
+	for _, m := range networkHostPattern.FindAllStringSubmatch(cmd, -1) {
+		host := m[1]
+
+		allowed := false
+		for _, re := range p.networkAllow {
+			if re.MatchString(host) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return fmt.Errorf("network access to %s denied by policy", host)
+		}
+	}
+
+	return nil
+}
+
+// Wrap adjusts name/args to run under this policy's isolation mode,
+// given workDir as the directory made available to the sandboxed
+// process. It returns name/args unchanged when Isolation is "none" or
+// unset.
+func (p *Policy) Wrap(name string, args []string, workDir string) (string, []string) {
+	switch p.Isolation {
+	case IsolationChroot:
+		chrootDir := p.ChrootDir
+		if chrootDir == "" {
+			chrootDir = workDir
+		}
+
+		wrapped := append([]string{chrootDir, name}, args...)
+		return "chroot", wrapped
+	case IsolationDocker:
+		image := p.DockerImage
+		if image == "" {
+			image = "alpine"
+		}
+
+		dockerArgs := []string{"run", "--rm", "-i", "-v", fmt.Sprintf("%s:/workspace", workDir), "-w", "/workspace"}
+		if p.NetworkDeny {
+			dockerArgs = append(dockerArgs, "--network", "none")
+		}
+		dockerArgs = append(dockerArgs, image, name)
+		dockerArgs = append(dockerArgs, args...)
+
+		return "docker", dockerArgs
+	default:
+		return name, args
+	}
+}
+
+// CheckWrite returns an error if path falls outside of the policy's
+// configured write roots. An empty write root list leaves writes
+// unrestricted under Isolation "none" (the default), but is implicitly
+// scoped to workDir — the directory actually bind-mounted or chrooted
+// for shell and python actions — under "chroot" or "docker" isolation,
+// so turning on isolation for its stated security purpose doesn't
+// silently leave file writes unconfined.
+func (p *Policy) CheckWrite(path, workDir string) error {
+	roots := p.WriteRoots
+	if len(roots) == 0 {
+		if p.Isolation == IsolationNone || p.Isolation == "" {
+			return nil
+		}
+		roots = []string{workDir}
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	for _, root := range roots {
+		rootAbs, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if abs == rootAbs || strings.HasPrefix(abs, rootAbs+string(filepath.Separator)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("write to %s denied by policy (outside write_roots)", path)
+}
@@ -0,0 +1,271 @@
+package sandbox
+
+import "testing"
+
+func newTestPolicy(t *testing.T, p Policy) *Policy {
+	t.Helper()
+
+	if err := p.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if err := p.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	return &p
+}
+
+func TestCheckShell(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		cmd     string
+		wantErr bool
+	}{
+		{
+			name:    "no rules allows anything",
+			policy:  Policy{},
+			cmd:     "rm -rf /",
+			wantErr: false,
+		},
+		{
+			name:    "deny list blocks a match",
+			policy:  Policy{ShellDeny: []string{`rm\s+-rf`}},
+			cmd:     "rm -rf /",
+			wantErr: true,
+		},
+		{
+			name:    "deny list allows a non-match",
+			policy:  Policy{ShellDeny: []string{`rm\s+-rf`}},
+			cmd:     "ls -la",
+			wantErr: false,
+		},
+		{
+			name:    "allow list permits a match",
+			policy:  Policy{ShellAllow: []string{`^ls\b`}},
+			cmd:     "ls -la",
+			wantErr: false,
+		},
+		{
+			name:    "allow list blocks a non-match",
+			policy:  Policy{ShellAllow: []string{`^ls\b`}},
+			cmd:     "rm -rf /",
+			wantErr: true,
+		},
+		{
+			name:    "deny takes precedence over allow",
+			policy:  Policy{ShellAllow: []string{`.*`}, ShellDeny: []string{`rm\s+-rf`}},
+			cmd:     "rm -rf /",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPolicy(t, tt.policy)
+
+			err := p.CheckShell(tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckShell(%q) error = %v, wantErr %v", tt.cmd, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		path    string
+		workDir string
+		wantErr bool
+	}{
+		{
+			name:    "no write roots allows anything under isolation none",
+			policy:  Policy{},
+			path:    "/etc/passwd",
+			wantErr: false,
+		},
+		{
+			name:    "path inside a write root is allowed",
+			policy:  Policy{WriteRoots: []string{"/workspace"}},
+			path:    "/workspace/output.txt",
+			wantErr: false,
+		},
+		{
+			name:    "path equal to a write root is allowed",
+			policy:  Policy{WriteRoots: []string{"/workspace"}},
+			path:    "/workspace",
+			wantErr: false,
+		},
+		{
+			name:    "path outside every write root is denied",
+			policy:  Policy{WriteRoots: []string{"/workspace"}},
+			path:    "/etc/passwd",
+			wantErr: true,
+		},
+		{
+			name:    "sibling path with shared prefix is denied",
+			policy:  Policy{WriteRoots: []string{"/workspace"}},
+			path:    "/workspace-evil/output.txt",
+			wantErr: true,
+		},
+		{
+			name:    "docker isolation with no write_roots falls back to workDir",
+			policy:  Policy{Isolation: IsolationDocker},
+			path:    "/etc/passwd",
+			workDir: "/workspace",
+			wantErr: true,
+		},
+		{
+			name:    "docker isolation with no write_roots allows a path under workDir",
+			policy:  Policy{Isolation: IsolationDocker},
+			path:    "/workspace/output.txt",
+			workDir: "/workspace",
+			wantErr: false,
+		},
+		{
+			name:    "chroot isolation with no write_roots falls back to workDir",
+			policy:  Policy{Isolation: IsolationChroot},
+			path:    "/etc/passwd",
+			workDir: "/workspace",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPolicy(t, tt.policy)
+
+			err := p.CheckWrite(tt.path, tt.workDir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckWrite(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		cmd     string
+		wantErr bool
+	}{
+		{
+			name:    "network deny not set allows anything",
+			policy:  Policy{},
+			cmd:     "curl https://example.com",
+			wantErr: false,
+		},
+		{
+			name:    "network deny blocks an unmatched host",
+			policy:  Policy{NetworkDeny: true},
+			cmd:     "curl https://evil.example.com/payload",
+			wantErr: true,
+		},
+		{
+			name:    "network deny allows a matched host",
+			policy:  Policy{NetworkDeny: true, NetworkAllow: []string{`^api\.example\.com$`}},
+			cmd:     "curl https://api.example.com/v1",
+			wantErr: false,
+		},
+		{
+			name:    "network deny with no urls in the command",
+			policy:  Policy{NetworkDeny: true},
+			cmd:     "echo hello",
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPolicy(t, tt.policy)
+
+			err := p.CheckNetwork(tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckNetwork(%q) error = %v, wantErr %v", tt.cmd, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateIsolation(t *testing.T) {
+	tests := []struct {
+		isolation Isolation
+		wantErr   bool
+	}{
+		{isolation: "", wantErr: false},
+		{isolation: IsolationNone, wantErr: false},
+		{isolation: IsolationChroot, wantErr: false},
+		{isolation: IsolationDocker, wantErr: false},
+		{isolation: "vm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.isolation), func(t *testing.T) {
+			p := &Policy{Isolation: tt.isolation}
+
+			err := p.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() with isolation %q error = %v, wantErr %v", tt.isolation, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		wantName string
+	}{
+		{
+			name:     "none leaves the command untouched",
+			policy:   Policy{},
+			wantName: "python3",
+		},
+		{
+			name:     "chroot wraps with the chroot binary",
+			policy:   Policy{Isolation: IsolationChroot, ChrootDir: "/sandbox"},
+			wantName: "chroot",
+		},
+		{
+			name:     "docker wraps with the docker binary",
+			policy:   Policy{Isolation: IsolationDocker},
+			wantName: "docker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPolicy(t, tt.policy)
+
+			name, _ := p.Wrap("python3", []string{"-c", "print(1)"}, "/workspace")
+			if name != tt.wantName {
+				t.Errorf("Wrap() name = %q, want %q", name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestWrapDockerUsesWorkDirForBindMount(t *testing.T) {
+	p := newTestPolicy(t, Policy{Isolation: IsolationDocker})
+
+	name, args := p.Wrap("python3", []string{"-c", "print(1)"}, "/workspace")
+	if name != "docker" {
+		t.Fatalf("Wrap() name = %q, want %q", name, "docker")
+	}
+
+	want := "/workspace:/workspace"
+	found := false
+	for _, a := range args {
+		if a == want {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("Wrap() args = %v, want a bind mount %q", args, want)
+	}
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const bingAPIURL = "https://api.bing.microsoft.com/v7.0/search"
+
+// BingProvider queries the Bing Web Search API.
+type BingProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewBingProvider(apiKey string) *BingProvider {
+	return &BingProvider{apiKey: apiKey, client: &http.Client{}}
+}
+
+type bingResponse struct {
+	WebPages struct {
+		Value []struct {
+			Name    string `json:"name"`
+			Snippet string `json:"snippet"`
+			URL     string `json:"url"`
+		} `json:"value"`
+	} `json:"webPages"`
+}
+
+func (p *BingProvider) Search(ctx context.Context, query string) ([]Result, error) {
+	q := url.Values{}
+	q.Set("q", query)
+
+	reqURL := fmt.Sprintf("%s?%s", bingAPIURL, q.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", p.apiKey)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bing: unexpected status code %d", res.StatusCode)
+	}
+
+	var body bingResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(body.WebPages.Value))
+	for _, r := range body.WebPages.Value {
+		results = append(results, Result{Title: r.Name, Desc: r.Snippet, URL: r.URL})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const braveAPIURL = "https://api.search.brave.com/res/v1/web/search"
+
+// BraveProvider queries the Brave Search API.
+type BraveProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewBraveProvider(apiKey string) *BraveProvider {
+	return &BraveProvider{apiKey: apiKey, client: &http.Client{}}
+}
+
+type braveResponse struct {
+	Web struct {
+		Results []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			URL         string `json:"url"`
+		} `json:"results"`
+	} `json:"web"`
+}
+
+func (p *BraveProvider) Search(ctx context.Context, query string) ([]Result, error) {
+	q := url.Values{}
+	q.Set("q", query)
+
+	reqURL := fmt.Sprintf("%s?%s", braveAPIURL, q.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Subscription-Token", p.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("brave: unexpected status code %d", res.StatusCode)
+	}
+
+	var body braveResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(body.Web.Results))
+	for _, r := range body.Web.Results {
+		results = append(results, Result{Title: r.Title, Desc: r.Description, URL: r.URL})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Cache is an on-disk cache of search results keyed by (provider,
+// query), so repeated searches during a task don't burn quota or get
+// rate-limited. Entries older than ttl are treated as misses, and the
+// least recently written entries are evicted once maxEntries is
+// exceeded.
+type Cache struct {
+	dir        string
+	ttl        time.Duration
+	maxEntries int
+}
+
+func NewCache(dir string, ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{dir: dir, ttl: ttl, maxEntries: maxEntries}
+}
+
+type cacheEntry struct {
+	StoredAt time.Time `json:"stored_at"`
+	Results  []Result  `json:"results"`
+}
+
+func (c *Cache) path(provider, query string) string {
+	sum := sha256.Sum256([]byte(provider + "\x00" + query))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the cached results for (provider, query), if present and
+// not expired.
+func (c *Cache) Get(provider, query string) ([]Result, bool) {
+	data, err := os.ReadFile(c.path(provider, query))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if time.Since(entry.StoredAt) > c.ttl {
+		return nil, false
+	}
+
+	return entry.Results, true
+}
+
+// Put stores results for (provider, query) and evicts the oldest
+// entries if the cache has grown past maxEntries.
+func (c *Cache) Put(provider, query string, results []Result) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	entry := cacheEntry{StoredAt: time.Now(), Results: results}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.path(provider, query), data, 0644); err != nil {
+		return err
+	}
+
+	return c.evict()
+}
+
+func (c *Cache) evict() error {
+	if c.maxEntries <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) <= c.maxEntries {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		iInfo, _ := entries[i].Info()
+		jInfo, _ := entries[j].Info()
+		if iInfo == nil || jInfo == nil {
+			return false
+		}
+		return iInfo.ModTime().Before(jInfo.ModTime())
+	})
+
+	for _, e := range entries[:len(entries)-c.maxEntries] {
+		if err := os.Remove(filepath.Join(c.dir, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package search
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCacheGetPutRoundTrip(t *testing.T) {
+	c := NewCache(t.TempDir(), time.Hour, 0)
+
+	if _, ok := c.Get("duckduckgo", "golang"); ok {
+		t.Fatal("Get on an empty cache returned a hit")
+	}
+
+	want := []Result{{Title: "Go", URL: "https://go.dev", Desc: "The Go language"}}
+	if err := c.Put("duckduckgo", "golang", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok := c.Get("duckduckgo", "golang")
+	if !ok {
+		t.Fatal("Get after Put returned a miss")
+	}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheGetExpired(t *testing.T) {
+	c := NewCache(t.TempDir(), time.Millisecond, 0)
+
+	if err := c.Put("duckduckgo", "golang", []Result{{Title: "Go"}}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, ok := c.Get("duckduckgo", "golang"); ok {
+		t.Fatal("Get returned a hit for an expired entry")
+	}
+}
+
+func TestCacheEvictsOldestEntries(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir, time.Hour, 2)
+
+	queries := []string{"a", "b", "c"}
+	for i, q := range queries {
+		if err := c.Put("duckduckgo", q, []Result{{Title: q}}); err != nil {
+			t.Fatalf("Put(%q): %v", q, err)
+		}
+
+		// Give each entry a distinct, strictly increasing mtime in the
+		// past, older than the write time of the next Put (which evicts
+		// before its own mtime is set here), so eviction order is
+		// deterministic regardless of filesystem timestamp resolution.
+		mtime := time.Now().Add(-time.Duration(len(queries)-i) * time.Minute)
+		if err := os.Chtimes(c.path("duckduckgo", q), mtime, mtime); err != nil {
+			t.Fatalf("Chtimes(%q): %v", q, err)
+		}
+	}
+
+	if err := c.evict(); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+
+	if _, ok := c.Get("duckduckgo", "a"); ok {
+		t.Error("oldest entry \"a\" survived eviction")
+	}
+	if _, ok := c.Get("duckduckgo", "b"); !ok {
+		t.Error("entry \"b\" was evicted but should have survived")
+	}
+	if _, ok := c.Get("duckduckgo", "c"); !ok {
+		t.Error("newest entry \"c\" was evicted")
+	}
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DuckDuckGoProvider scrapes DuckDuckGo Lite. It requires no API key or
+// endpoint, so it's the default fallback when no other backend is
+// configured.
+type DuckDuckGoProvider struct {
+	client *http.Client
+}
+
+func NewDuckDuckGoProvider() *DuckDuckGoProvider {
+	return &DuckDuckGoProvider{client: &http.Client{}}
+}
+
+func (p *DuckDuckGoProvider) Search(ctx context.Context, query string) ([]Result, error) {
+	payload := url.Values{}
+	payload.Add("q", query)
+	payload.Add("kl", "")
+	payload.Add("df", "")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://lite.duckduckgo.com/lite/", strings.NewReader(payload.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://lite.duckduckgo.com")
+	req.Header.Set("User-Agent", "gptask")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("duckduckgo: unexpected status code %d", res.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Result{}
+	doc.Find("body > form > div > table:nth-child(7) > tbody > tr > td").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if len(text) == 0 {
+			return
+		}
+
+		index := i / 8
+		if len(results) <= index {
+			results = append(results, Result{})
+		}
+
+		switch {
+		case i%8 == 1:
+			results[index].Title = text
+			results[index].URL, _ = s.Find("a").Attr("href")
+		case i%8 == 3:
+			results[index].Desc = text
+		}
+	})
+
+	return results, nil
+}
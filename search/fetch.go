@@ -0,0 +1,42 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// FetchPage retrieves pageURL and returns its readable text, stripped
+// of scripts, styles and markup, so the model can read the page itself
+// rather than just a search snippet.
+func FetchPage(ctx context.Context, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "gptask")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch-page: unexpected status code %d for %s", res.StatusCode, pageURL)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	doc.Find("script, style, nav, header, footer").Remove()
+
+	text := strings.Join(strings.Fields(doc.Find("body").Text()), " ")
+
+	return text, nil
+}
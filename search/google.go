@@ -0,0 +1,68 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const googleAPIURL = "https://www.googleapis.com/customsearch/v1"
+
+// GoogleProvider queries the Google Programmable (Custom) Search JSON
+// API. cx is the search engine ID configured at
+// programmablesearchengine.google.com.
+type GoogleProvider struct {
+	apiKey string
+	cx     string
+	client *http.Client
+}
+
+func NewGoogleProvider(apiKey, cx string) *GoogleProvider {
+	return &GoogleProvider{apiKey: apiKey, cx: cx, client: &http.Client{}}
+}
+
+type googleResponse struct {
+	Items []struct {
+		Title   string `json:"title"`
+		Snippet string `json:"snippet"`
+		Link    string `json:"link"`
+	} `json:"items"`
+}
+
+func (p *GoogleProvider) Search(ctx context.Context, query string) ([]Result, error) {
+	q := url.Values{}
+	q.Set("key", p.apiKey)
+	q.Set("cx", p.cx)
+	q.Set("q", query)
+
+	reqURL := fmt.Sprintf("%s?%s", googleAPIURL, q.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google: unexpected status code %d", res.StatusCode)
+	}
+
+	var body googleResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(body.Items))
+	for _, r := range body.Items {
+		results = append(results, Result{Title: r.Title, Desc: r.Snippet, URL: r.Link})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/summerwind/gptask/config"
+)
+
+// Result is a single search result returned by a Provider.
+type Result struct {
+	Title string `json:"title"`
+	Desc  string `json:"desc"`
+	URL   string `json:"url"`
+}
+
+// Provider is implemented by each supported search backend.
+type Provider interface {
+	Search(ctx context.Context, query string) ([]Result, error)
+}
+
+// NewProvider builds the Provider selected by c.SearchBackend.
+func NewProvider(c *config.Config) (Provider, error) {
+	switch c.SearchBackend {
+	case "", "duckduckgo":
+		return NewDuckDuckGoProvider(), nil
+	case "searxng":
+		if c.SearchEndpoint == "" {
+			return nil, fmt.Errorf("endpoint must be set for the searxng search backend")
+		}
+		return NewSearxNGProvider(c.SearchEndpoint), nil
+	case "bing":
+		if c.SearchAPIKey == "" {
+			return nil, fmt.Errorf("API key must be set for the bing search backend")
+		}
+		return NewBingProvider(c.SearchAPIKey), nil
+	case "google":
+		if c.SearchAPIKey == "" || c.SearchCX == "" {
+			return nil, fmt.Errorf("API key and search CX must be set for the google search backend")
+		}
+		return NewGoogleProvider(c.SearchAPIKey, c.SearchCX), nil
+	case "brave":
+		if c.SearchAPIKey == "" {
+			return nil, fmt.Errorf("API key must be set for the brave search backend")
+		}
+		return NewBraveProvider(c.SearchAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported search backend: %s", c.SearchBackend)
+	}
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SearxNGProvider queries a SearxNG instance's JSON API.
+type SearxNGProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewSearxNGProvider(endpoint string) *SearxNGProvider {
+	return &SearxNGProvider{endpoint: endpoint, client: &http.Client{}}
+}
+
+type searxngResponse struct {
+	Results []struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+		URL     string `json:"url"`
+	} `json:"results"`
+}
+
+func (p *SearxNGProvider) Search(ctx context.Context, query string) ([]Result, error) {
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("format", "json")
+
+	reqURL := fmt.Sprintf("%s/search?%s", p.endpoint, q.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("searxng: unexpected status code %d", res.StatusCode)
+	}
+
+	var body searxngResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(body.Results))
+	for _, r := range body.Results {
+		results = append(results, Result{Title: r.Title, Desc: r.Content, URL: r.URL})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const anthropicAPIURL = "https://api.anthropic.com/v1/messages"
+
+// AnthropicProvider talks to the Anthropic Messages API.
+type AnthropicProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewAnthropicProvider(apiKey string) *AnthropicProvider {
+	return &AnthropicProvider{
+		apiKey: apiKey,
+		client: &http.Client{},
+	}
+}
+
+type anthropicRequest struct {
+	Model         string             `json:"model"`
+	Messages      []anthropicMessage `json:"messages"`
+	System        string             `json:"system,omitempty"`
+	MaxTokens     int                `json:"max_tokens"`
+	Temperature   float32            `json:"temperature"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (p *AnthropicProvider) GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	reqBody := anthropicRequest{
+		Model:         opts.Model,
+		MaxTokens:     4096,
+		Temperature:   opts.Temperature,
+		StopSequences: opts.Stop,
+	}
+
+	for _, m := range messages {
+		if m.Role == "system" {
+			reqBody.System = m.Content
+			continue
+		}
+		reqBody.Messages = append(reqBody.Messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("anthropic: unexpected status code %d", res.StatusCode)
+	}
+
+	var chatRes anthropicResponse
+	if err := json.NewDecoder(res.Body).Decode(&chatRes); err != nil {
+		return "", err
+	}
+	if len(chatRes.Content) == 0 {
+		return "", nil
+	}
+
+	return chatRes.Content[0].Text, nil
+}
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Pinned is the number of leading messages that are never touched by
+// Compact or Runner.rewind: the system prompt and the initial user
+// task.
+const Pinned = 2
+
+// summarizePrompt is prepended to the stale messages handed to the
+// model when a compaction pass runs.
+const summarizePrompt = "Summarize the following steps and their outcomes in 200 tokens or fewer, preserving file paths, key findings, and open TODOs:\n\n"
+
+// Compactor keeps a Session's conversation within a model's context
+// window by replacing older steps with a single summary once the
+// estimated token count grows past a configurable fraction of the
+// budget.
+type Compactor struct {
+	// MaxTokens is the model's context window, in tokens. Compact is a
+	// no-op when MaxTokens is zero.
+	MaxTokens int
+
+	// Threshold is the fraction of MaxTokens that triggers a compaction
+	// pass (e.g. 0.8 compacts once the conversation reaches 80% of
+	// MaxTokens).
+	Threshold float64
+}
+
+// NewCompactor returns a Compactor that triggers once a Session's
+// estimated token count reaches threshold*maxTokens.
+func NewCompactor(maxTokens int, threshold float64) *Compactor {
+	return &Compactor{MaxTokens: maxTokens, Threshold: threshold}
+}
+
+// EstimateTokens approximates the token count of s using a simple
+// character-based heuristic (~4 characters per token) rather than a
+// model-specific tokenizer, since the estimate only needs to be close
+// enough to stay under the context window.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+func (c *Compactor) tokenCount(messages []Message) int {
+	total := 0
+	for _, m := range messages {
+		total += EstimateTokens(m.Content)
+	}
+	return total
+}
+
+// Compact summarizes the oldest step messages in s into a single
+// system message once the conversation has reached c.Threshold of
+// c.MaxTokens. It leaves the system prompt and the initial user task
+// (the first two messages) pinned, as well as the most recent
+// assistant/observation pair, and reports whether it compacted.
+func (c *Compactor) Compact(ctx context.Context, s *Session) (bool, error) {
+	if c.MaxTokens <= 0 {
+		return false, nil
+	}
+
+	if c.tokenCount(s.Messages) < int(float64(c.MaxTokens)*c.Threshold) {
+		return false, nil
+	}
+
+	cut := len(s.Messages) - 2
+	if cut <= Pinned {
+		return false, nil
+	}
+
+	stale := s.Messages[Pinned:cut]
+
+	var sb strings.Builder
+	for _, m := range stale {
+		fmt.Fprintf(&sb, "%s: %s\n", m.Role, m.Content)
+	}
+
+	summary, err := s.Provider.GetCompletion(ctx, []Message{
+		{Role: "user", Content: summarizePrompt + sb.String()},
+	}, CompletionOptions{Model: s.Model, Temperature: 0.0})
+	if err != nil {
+		return false, err
+	}
+
+	messages := make([]Message, 0, Pinned+2)
+	messages = append(messages, s.Messages[:Pinned]...)
+	messages = append(messages, Message{Role: "system", Content: "summary of earlier steps: " + strings.TrimSpace(summary)})
+	messages = append(messages, s.Messages[cut:]...)
+
+	s.Messages = messages
+
+	return true, nil
+}
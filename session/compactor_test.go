@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeProvider is a Provider that returns a fixed summary and records
+// the prompt it was asked to summarize.
+type fakeProvider struct {
+	summary    string
+	lastPrompt string
+}
+
+func (p *fakeProvider) GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	if len(messages) > 0 {
+		p.lastPrompt = messages[0].Content
+	}
+	return p.summary, nil
+}
+
+func newTestSession(stepCount int) *Session {
+	s := &Session{
+		Provider: &fakeProvider{summary: "summary"},
+		Model:    "test-model",
+		Messages: []Message{
+			{Role: "system", Content: "system prompt"},
+			{Role: "user", Content: "initial task"},
+		},
+	}
+
+	for i := 0; i < stepCount; i++ {
+		s.AddAssistantMessage(fmt.Sprintf("step %d action", i))
+		s.AddUserMessage(fmt.Sprintf("step %d observation", i))
+	}
+
+	return s
+}
+
+func TestCompactorCompact(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxTokens     int
+		threshold     float64
+		stepCount     int
+		wantCompacted bool
+		wantMessages  int
+	}{
+		{
+			name:          "disabled when MaxTokens is zero",
+			maxTokens:     0,
+			threshold:     0.8,
+			stepCount:     20,
+			wantCompacted: false,
+			wantMessages:  2 + 20*2,
+		},
+		{
+			name:          "under threshold does nothing",
+			maxTokens:     1_000_000,
+			threshold:     0.8,
+			stepCount:     5,
+			wantCompacted: false,
+			wantMessages:  2 + 5*2,
+		},
+		{
+			name:          "over threshold compacts down to pinned + summary + last pair",
+			maxTokens:     10,
+			threshold:     0.1,
+			stepCount:     5,
+			wantCompacted: true,
+			wantMessages:  Pinned + 1 + 2,
+		},
+		{
+			name:          "not enough history to compact without losing the last pair",
+			maxTokens:     10,
+			threshold:     0.1,
+			stepCount:     0,
+			wantCompacted: false,
+			wantMessages:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(tt.stepCount)
+			c := NewCompactor(tt.maxTokens, tt.threshold)
+
+			compacted, err := c.Compact(context.Background(), s)
+			if err != nil {
+				t.Fatalf("Compact: %v", err)
+			}
+			if compacted != tt.wantCompacted {
+				t.Errorf("Compact() = %v, want %v", compacted, tt.wantCompacted)
+			}
+			if len(s.Messages) != tt.wantMessages {
+				t.Errorf("len(s.Messages) = %d, want %d", len(s.Messages), tt.wantMessages)
+			}
+
+			if tt.wantCompacted {
+				if s.Messages[0].Role != "system" || s.Messages[1].Content != "initial task" {
+					t.Errorf("pinned messages were not preserved: %+v", s.Messages[:Pinned])
+				}
+				if !strings.Contains(s.Messages[Pinned].Content, "summary") {
+					t.Errorf("compacted message does not contain the summary: %q", s.Messages[Pinned].Content)
+				}
+				last := s.Messages[len(s.Messages)-1]
+				if !strings.Contains(last.Content, fmt.Sprintf("step %d observation", tt.stepCount-1)) {
+					t.Errorf("most recent observation was not preserved, got %q", last.Content)
+				}
+			}
+		})
+	}
+}
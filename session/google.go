@@ -0,0 +1,107 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const googleAPIURL = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s"
+
+// GoogleProvider talks to the Gemini generateContent API.
+type GoogleProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewGoogleProvider(apiKey string) *GoogleProvider {
+	return &GoogleProvider{
+		apiKey: apiKey,
+		client: &http.Client{},
+	}
+}
+
+type googleRequest struct {
+	Contents          []googleContent        `json:"contents"`
+	SystemInstruction *googleContent         `json:"systemInstruction,omitempty"`
+	GenerationConfig  googleGenerationConfig `json:"generationConfig"`
+}
+
+type googleContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []googlePart `json:"parts"`
+}
+
+type googlePart struct {
+	Text string `json:"text"`
+}
+
+type googleGenerationConfig struct {
+	Temperature   float32  `json:"temperature"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+}
+
+type googleResponse struct {
+	Candidates []struct {
+		Content googleContent `json:"content"`
+	} `json:"candidates"`
+}
+
+func (p *GoogleProvider) GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	reqBody := googleRequest{
+		GenerationConfig: googleGenerationConfig{
+			Temperature:   opts.Temperature,
+			StopSequences: opts.Stop,
+		},
+	}
+
+	for _, m := range messages {
+		if m.Role == "system" {
+			reqBody.SystemInstruction = &googleContent{Parts: []googlePart{{Text: m.Content}}}
+			continue
+		}
+
+		role := "user"
+		if m.Role == "assistant" {
+			role = "model"
+		}
+		reqBody.Contents = append(reqBody.Contents, googleContent{
+			Role:  role,
+			Parts: []googlePart{{Text: m.Content}},
+		})
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf(googleAPIURL, opts.Model, p.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("google: unexpected status code %d", res.StatusCode)
+	}
+
+	var chatRes googleResponse
+	if err := json.NewDecoder(res.Body).Decode(&chatRes); err != nil {
+		return "", err
+	}
+	if len(chatRes.Candidates) == 0 || len(chatRes.Candidates[0].Content.Parts) == 0 {
+		return "", nil
+	}
+
+	return chatRes.Candidates[0].Content.Parts[0].Text, nil
+}
@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec lets GRPCProvider call the gptask.Predict service (see
+// proto/gptask.proto) without protoc-generated message types: requests
+// and replies are plain Go structs marshaled as JSON rather than
+// protobuf, so a model server only needs to speak gRPC with the "json"
+// codec instead of linking in generated stubs.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// GRPCProvider calls a locally or self hosted model server implementing
+// the gptask.Predict gRPC service described in proto/gptask.proto.
+type GRPCProvider struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCProvider dials a gRPC model server at endpoint (host:port). The
+// connection is insecure (no TLS), matching the other local backend
+// (ollama), which assumes a trusted local network.
+func NewGRPCProvider(endpoint string) (*GRPCProvider, error) {
+	conn, err := grpc.Dial(endpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(jsonCodec{}.Name())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("grpc: %w", err)
+	}
+
+	return &GRPCProvider{conn: conn}, nil
+}
+
+type grpcPredictRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature float32   `json:"temperature"`
+	Stop        []string  `json:"stop"`
+}
+
+type grpcPredictResponse struct {
+	Text string `json:"text"`
+}
+
+func (p *GRPCProvider) GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	req := grpcPredictRequest{
+		Model:       opts.Model,
+		Messages:    messages,
+		Temperature: opts.Temperature,
+		Stop:        opts.Stop,
+	}
+
+	var res grpcPredictResponse
+	if err := p.conn.Invoke(ctx, "/gptask.Predict/Predict", &req, &res); err != nil {
+		return "", fmt.Errorf("grpc: %w", err)
+	}
+
+	return res.Text, nil
+}
+
+// Embedding calls the model server's Embedding RPC and returns the
+// resulting vector for text.
+func (p *GRPCProvider) Embedding(ctx context.Context, text string) ([]float32, error) {
+	req := struct {
+		Text string `json:"text"`
+	}{Text: text}
+
+	var res struct {
+		Vector []float32 `json:"vector"`
+	}
+
+	if err := p.conn.Invoke(ctx, "/gptask.Predict/Embedding", &req, &res); err != nil {
+		return nil, fmt.Errorf("grpc: %w", err)
+	}
+
+	return res.Vector, nil
+}
+
+// Health calls the model server's Health RPC, returning an error if the
+// server is unreachable or reports itself unhealthy.
+func (p *GRPCProvider) Health(ctx context.Context) error {
+	var res struct {
+		Ok bool `json:"ok"`
+	}
+
+	if err := p.conn.Invoke(ctx, "/gptask.Predict/Health", &struct{}{}, &res); err != nil {
+		return fmt.Errorf("grpc: %w", err)
+	}
+	if !res.Ok {
+		return fmt.Errorf("grpc: model server reported unhealthy")
+	}
+
+	return nil
+}
+
+// Close releases the underlying gRPC connection.
+func (p *GRPCProvider) Close() error {
+	return p.conn.Close()
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// OllamaProvider talks to a local Ollama server, letting gptask run
+// against local models (e.g. Llama, Mistral) without a cloud provider.
+type OllamaProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewOllamaProvider(endpoint string) *OllamaProvider {
+	return &OllamaProvider{
+		endpoint: endpoint,
+		client:   &http.Client{},
+	}
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaOptions struct {
+	Temperature float32  `json:"temperature"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message ollamaMessage `json:"message"`
+}
+
+func (p *OllamaProvider) GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	reqBody := ollamaRequest{
+		Model:  opts.Model,
+		Stream: false,
+		Options: ollamaOptions{
+			Temperature: opts.Temperature,
+			Stop:        opts.Stop,
+		},
+	}
+	for _, m := range messages {
+		reqBody.Messages = append(reqBody.Messages, ollamaMessage{Role: m.Role, Content: m.Content})
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/chat", p.endpoint), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ollama: unexpected status code %d", res.StatusCode)
+	}
+
+	var chatRes ollamaResponse
+	if err := json.NewDecoder(res.Body).Decode(&chatRes); err != nil {
+		return "", err
+	}
+
+	return chatRes.Message.Content, nil
+}
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAIProvider talks to the OpenAI chat completion API.
+type OpenAIProvider struct {
+	client *openai.Client
+}
+
+func NewOpenAIProvider(apiKey string) *OpenAIProvider {
+	return &OpenAIProvider{
+		client: openai.NewClient(apiKey),
+	}
+}
+
+func (p *OpenAIProvider) GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	req := openai.ChatCompletionRequest{
+		Model:       opts.Model,
+		Messages:    toOpenAIMessages(messages),
+		Temperature: opts.Temperature,
+		Stop:        opts.Stop,
+	}
+
+	res, err := p.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Choices[0].Message.Content, nil
+}
+
+// GetCompletionStream streams the completion as a series of token
+// deltas using go-openai's streaming API.
+func (p *OpenAIProvider) GetCompletionStream(ctx context.Context, messages []Message, opts CompletionOptions) (<-chan string, <-chan error) {
+	chunks := make(chan string)
+	errs := make(chan error, 1)
+
+	req := openai.ChatCompletionRequest{
+		Model:       opts.Model,
+		Messages:    toOpenAIMessages(messages),
+		Temperature: opts.Temperature,
+		Stop:        opts.Stop,
+		Stream:      true,
+	}
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		stream, err := p.client.CreateChatCompletionStream(ctx, req)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer stream.Close()
+
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(res.Choices) == 0 {
+				continue
+			}
+
+			chunks <- res.Choices[0].Delta.Content
+		}
+	}()
+
+	return chunks, errs
+}
+
+func toOpenAIMessages(messages []Message) []openai.ChatCompletionMessage {
+	out := make([]openai.ChatCompletionMessage, len(messages))
+	for i, m := range messages {
+		out[i] = openai.ChatCompletionMessage{
+			Role:    m.Role,
+			Content: m.Content,
+		}
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/summerwind/gptask/config"
+)
+
+// Message is a single chat message exchanged with a Provider. It mirrors
+// the subset of fields that every supported backend can represent.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// CompletionOptions carries the per-request parameters passed to a
+// Provider's GetCompletion call.
+type CompletionOptions struct {
+	Model       string
+	Temperature float32
+	Stop        []string
+}
+
+// Provider is implemented by each supported LLM backend (OpenAI,
+// Anthropic, Ollama, ...). It turns a conversation into the next
+// assistant reply.
+type Provider interface {
+	GetCompletion(ctx context.Context, messages []Message, opts CompletionOptions) (string, error)
+}
+
+// StreamingProvider is implemented by backends that can stream a
+// completion as a series of token deltas rather than returning it all
+// at once. The chunks channel is closed when the reply is complete; the
+// errs channel carries at most one error and is closed alongside it.
+type StreamingProvider interface {
+	Provider
+	GetCompletionStream(ctx context.Context, messages []Message, opts CompletionOptions) (<-chan string, <-chan error)
+}
+
+// NewProvider builds the Provider selected by c.Backend.
+func NewProvider(c *config.Config) (Provider, error) {
+	switch c.Backend {
+	case "", "openai":
+		if c.APIKey == "" {
+			return nil, errors.New("API key must be set for the openai backend")
+		}
+		return NewOpenAIProvider(c.APIKey), nil
+	case "anthropic":
+		if c.APIKey == "" {
+			return nil, errors.New("API key must be set for the anthropic backend")
+		}
+		return NewAnthropicProvider(c.APIKey), nil
+	case "ollama":
+		if c.Endpoint == "" {
+			return nil, errors.New("endpoint must be set for the ollama backend")
+		}
+		return NewOllamaProvider(c.Endpoint), nil
+	case "google":
+		if c.APIKey == "" {
+			return nil, errors.New("API key must be set for the google backend")
+		}
+		return NewGoogleProvider(c.APIKey), nil
+	case "grpc":
+		if c.Endpoint == "" {
+			return nil, errors.New("endpoint must be set for the grpc backend")
+		}
+		return NewGRPCProvider(c.Endpoint)
+	default:
+		return nil, fmt.Errorf("unsupported backend: %s", c.Backend)
+	}
+}
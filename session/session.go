@@ -2,57 +2,121 @@ package session
 
 import (
 	"context"
-	_ "embed"
 	"strings"
 
-	"github.com/sashabaranov/go-openai"
 	"github.com/summerwind/gptask/config"
 )
 
+// defaultStop is the stop sequence used when Config.Stop is empty, so
+// the model doesn't go on to fabricate its own observation.
+var defaultStop = []string{"observation:"}
+
+// Session maintains the conversation history for a single task and
+// delegates completions to the configured Provider.
 type Session struct {
-	Client   *openai.Client
-	Model    string
-	Messages []openai.ChatCompletionMessage
+	Provider    Provider
+	Model       string
+	Temperature float32
+	Stop        []string
+	Messages    []Message
 }
 
-func New(c *config.Config, systemPrompt string) *Session {
+func New(c *config.Config, systemPrompt string) (*Session, error) {
+	provider, err := NewProvider(c)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := c.Stop
+	if len(stop) == 0 {
+		stop = defaultStop
+	}
+
 	return &Session{
-		Client: openai.NewClient(c.APIKey),
-		Model:  c.Model,
-		Messages: []openai.ChatCompletionMessage{
+		Provider:    provider,
+		Model:       c.Model,
+		Temperature: c.Temperature,
+		Stop:        stop,
+		Messages: []Message{
 			{Role: "system", Content: systemPrompt},
 		},
-	}
+	}, nil
 }
 
 func (s *Session) AddUserMessage(prompt string) {
-	s.Messages = append(s.Messages, openai.ChatCompletionMessage{
+	s.Messages = append(s.Messages, Message{
 		Role:    "user",
 		Content: prompt,
 	})
 }
 
 func (s *Session) AddAssistantMessage(prompt string) {
-	s.Messages = append(s.Messages, openai.ChatCompletionMessage{
+	s.Messages = append(s.Messages, Message{
 		Role:    "assistant",
 		Content: prompt,
 	})
 }
 
+// Fork returns a new Session sharing the same provider and model but
+// whose history is truncated to the first n messages. This lets a
+// caller re-prompt from an earlier step without mutating s.
+func (s *Session) Fork(n int) *Session {
+	messages := make([]Message, n)
+	copy(messages, s.Messages[:n])
+
+	return &Session{
+		Provider:    s.Provider,
+		Model:       s.Model,
+		Temperature: s.Temperature,
+		Stop:        s.Stop,
+		Messages:    messages,
+	}
+}
+
 func (s *Session) GetCompletion(ctx context.Context) (string, error) {
-	req := openai.ChatCompletionRequest{
+	opts := CompletionOptions{
 		Model:       s.Model,
-		Messages:    s.Messages,
-		Temperature: 0.0,
-		Stop:        []string{"observation:"},
+		Temperature: s.Temperature,
+		Stop:        s.Stop,
 	}
 
-	res, err := s.Client.CreateChatCompletion(ctx, req)
+	reply, err := s.Provider.GetCompletion(ctx, s.Messages, opts)
 	if err != nil {
 		return "", err
 	}
-	reply := res.Choices[0].Message.Content
-	reply = strings.TrimRight(reply, "\n")
 
-	return reply, nil
+	return strings.TrimRight(reply, "\n"), nil
+}
+
+// GetCompletionStream behaves like GetCompletion but streams the reply
+// as token deltas when the underlying Provider supports it. Otherwise
+// the full reply is delivered as a single chunk once it's ready.
+func (s *Session) GetCompletionStream(ctx context.Context) (<-chan string, <-chan error) {
+	opts := CompletionOptions{
+		Model:       s.Model,
+		Temperature: s.Temperature,
+		Stop:        s.Stop,
+	}
+
+	if sp, ok := s.Provider.(StreamingProvider); ok {
+		return sp.GetCompletionStream(ctx, s.Messages, opts)
+	}
+
+	chunks := make(chan string, 1)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		reply, err := s.Provider.GetCompletion(ctx, s.Messages, opts)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		chunks <- reply
+	}()
+
+	return chunks, errs
 }
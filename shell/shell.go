@@ -15,21 +15,37 @@ const magicDelimiter = "GPTASK-COMMAND-END"
 
 type Shell struct {
 	workDir   string
+	cmdName   string
+	cmdArgs   []string
 	cmd       *exec.Cmd
 	stdinPipe io.WriteCloser
 	stdoutCh  chan string
 	stderrCh  chan string
 }
 
-func New() *Shell {
+// New returns a Shell seeded with workDir, so WorkDir() reports the
+// configured working directory even before the first command runs.
+func New(workDir string) *Shell {
+	return NewWithCommand(workDir, "bash", "-o", "pipefail", "-s")
+}
+
+// NewWithCommand returns a Shell whose persistent session is started
+// with name/args instead of the default "bash -o pipefail -s", e.g. to wrap it in a
+// chroot or docker command per a sandbox.Policy's isolation mode. It's
+// seeded with workDir, so WorkDir() reports the configured working
+// directory even before the first command runs.
+func NewWithCommand(workDir, name string, args ...string) *Shell {
 	return &Shell{
+		workDir:  workDir,
+		cmdName:  name,
+		cmdArgs:  args,
 		stdoutCh: make(chan string),
 		stderrCh: make(chan string),
 	}
 }
 
 func (s *Shell) Start() error {
-	s.cmd = exec.Command("bash", "-o", "pipefail", "-s")
+	s.cmd = exec.Command(s.cmdName, s.cmdArgs...)
 
 	stdin, err := s.cmd.StdinPipe()
 	if err != nil {
@@ -13,6 +13,12 @@ type Step struct {
 	Observation string `json:"observation,omitempty"`
 }
 
+// Validate checks that s is structurally complete. It deliberately does
+// not check s.Action against a fixed set of names: the set of valid
+// actions is whatever's registered in the runner's tool.Registry
+// (built-ins plus any external tool servers), and Runner.Dispatch's
+// errInvalidAction is the single source of truth for an unrecognized
+// one.
 func (s *Step) Validate() error {
 	if s.Action == "done" {
 		return nil
@@ -21,21 +27,21 @@ func (s *Step) Validate() error {
 	if s.Thought == "" {
 		return errors.New("thought must be specified")
 	}
+	if s.Action == "" {
+		return errors.New("action must be specified")
+	}
 	if s.Input == "" {
 		return errors.New("input must be specified")
 	}
 
-	switch s.Action {
-	case "file", "python", "shell", "search":
-		// valid.
-	default:
-		return errors.New("invalid action value")
-	}
-
 	return nil
 }
 
-func decodeStep(msg string) (*Step, error) {
+// decodePartialStep parses as much of a Step as msg currently contains.
+// It never errors and never requires the input block to be closed,
+// which makes it safe to call repeatedly on a growing buffer while a
+// reply is still streaming in.
+func decodePartialStep(msg string) *Step {
 	var (
 		s     Step
 		block bool
@@ -77,12 +83,36 @@ func decodeStep(msg string) (*Step, error) {
 		i += 1
 	}
 
-	err := s.Validate()
-	if err != nil {
+	return &s
+}
+
+// stepDecoder incrementally parses a Step from a stream of token
+// deltas, so a partial thought/action can be rendered as soon as it
+// arrives instead of waiting for the whole reply.
+type stepDecoder struct {
+	buf strings.Builder
+}
+
+func newStepDecoder() *stepDecoder {
+	return &stepDecoder{}
+}
+
+// feed appends delta to the buffered reply and returns the Step parsed
+// from it so far. The returned Step is not validated and may be only
+// partially filled in.
+func (d *stepDecoder) feed(delta string) *Step {
+	d.buf.WriteString(delta)
+	return decodePartialStep(d.buf.String())
+}
+
+func decodeStep(msg string) (*Step, error) {
+	s := decodePartialStep(msg)
+
+	if err := s.Validate(); err != nil {
 		return nil, err
 	}
 
-	return &s, nil
+	return s, nil
 }
 
 func encodeStep(s *Step) string {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodePartialStep(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want *Step
+	}{
+		{
+			name: "empty",
+			msg:  "",
+			want: &Step{},
+		},
+		{
+			name: "thought only",
+			msg:  "thought: checking the file exists",
+			want: &Step{Thought: "checking the file exists"},
+		},
+		{
+			name: "thought and action, no input yet",
+			msg:  "thought: list the directory\naction: shell",
+			want: &Step{Thought: "list the directory", Action: "shell"},
+		},
+		{
+			name: "input block still open",
+			msg:  "thought: t\naction: shell\ninput:\n```\nls -la",
+			want: &Step{Thought: "t", Action: "shell", Input: "ls -la"},
+		},
+		{
+			name: "complete step",
+			msg:  "thought: t\naction: shell\ninput:\n```\nls -la\n```\n",
+			want: &Step{Thought: "t", Action: "shell", Input: "ls -la"},
+		},
+		{
+			name: "multi-line input",
+			msg:  "thought: t\naction: file\ninput:\n```\nline one\nline two\n```\n",
+			want: &Step{Thought: "t", Action: "file", Input: "line one\nline two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodePartialStep(tt.msg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodePartialStep(%q) = %+v, want %+v", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepDecoderFeed(t *testing.T) {
+	d := newStepDecoder()
+
+	deltas := []string{
+		"thought: listing ",
+		"the directory\naction: shell\ninput:\n",
+		"```\nls -la\n",
+		"```\n",
+	}
+
+	var last *Step
+	for _, delta := range deltas {
+		last = d.feed(delta)
+	}
+
+	want := &Step{Thought: "listing the directory", Action: "shell", Input: "ls -la"}
+	if !reflect.DeepEqual(last, want) {
+		t.Errorf("after feeding all deltas, got %+v, want %+v", last, want)
+	}
+}
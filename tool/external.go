@@ -0,0 +1,112 @@
+package tool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// External is a Tool backed by a subprocess speaking a small
+// line-delimited JSON-RPC protocol over stdin/stdout. This lets users
+// add their own tools (git, kubectl, a browser, ...) without
+// recompiling gptask.
+type External struct {
+	cmd    *exec.Cmd
+	in     *json.Encoder
+	out    *bufio.Scanner
+	schema Schema
+}
+
+type externalRequest struct {
+	Method string `json:"method"`
+	Input  string `json:"input,omitempty"`
+}
+
+type externalSchemaResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Input       string `json:"input"`
+}
+
+type externalRunResponse struct {
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewExternal starts command as a subprocess and performs the schema
+// handshake (a single "schema" request/response pair), returning a Tool
+// that forwards Run calls to it over stdio.
+func NewExternal(command string, args ...string) (*External, error) {
+	cmd := exec.Command(command, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e := &External{
+		cmd: cmd,
+		in:  json.NewEncoder(stdin),
+		out: bufio.NewScanner(stdout),
+	}
+	e.out.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if err := e.in.Encode(externalRequest{Method: "schema"}); err != nil {
+		return nil, err
+	}
+	if !e.out.Scan() {
+		return nil, fmt.Errorf("tool server %s closed before sending its schema", command)
+	}
+
+	var schema externalSchemaResponse
+	if err := json.Unmarshal(e.out.Bytes(), &schema); err != nil {
+		return nil, fmt.Errorf("tool server %s sent an invalid schema: %w", command, err)
+	}
+
+	e.schema = Schema{
+		Name:        schema.Name,
+		Description: schema.Description,
+		Input:       schema.Input,
+	}
+
+	return e, nil
+}
+
+func (e *External) Name() string { return e.schema.Name }
+
+func (e *External) Describe() Schema { return e.schema }
+
+func (e *External) Run(ctx context.Context, input string) (string, error) {
+	if err := e.in.Encode(externalRequest{Method: "run", Input: input}); err != nil {
+		return "", err
+	}
+	if !e.out.Scan() {
+		return "", fmt.Errorf("tool server %s closed without responding", e.schema.Name)
+	}
+
+	var res externalRunResponse
+	if err := json.Unmarshal(e.out.Bytes(), &res); err != nil {
+		return "", fmt.Errorf("tool server %s sent an invalid response: %w", e.schema.Name, err)
+	}
+	if res.Error != "" {
+		return "", fmt.Errorf("tool server %s: %s", e.schema.Name, res.Error)
+	}
+
+	return res.Result, nil
+}
+
+// Close terminates the subprocess.
+func (e *External) Close() error {
+	return e.cmd.Process.Kill()
+}
@@ -0,0 +1,56 @@
+package tool
+
+import "context"
+
+// Schema describes a Tool to the model so it knows when and how to use
+// it.
+type Schema struct {
+	Name        string
+	Description string
+	Input       string // human-readable description of the expected input
+}
+
+// Tool is a single capability the runner can dispatch an action to,
+// such as running a shell command or searching the web.
+type Tool interface {
+	Name() string
+	Describe() Schema
+	Run(ctx context.Context, input string) (string, error)
+}
+
+// Registry holds the set of tools available to a Runner, keyed by
+// name, in registration order.
+type Registry struct {
+	tools map[string]Tool
+	order []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]Tool)}
+}
+
+// Register adds t to the registry. Registering a tool under a name
+// that's already taken replaces the previous one in place.
+func (r *Registry) Register(t Tool) {
+	name := t.Name()
+	if _, exists := r.tools[name]; !exists {
+		r.order = append(r.order, name)
+	}
+	r.tools[name] = t
+}
+
+// Get returns the tool registered under name, if any.
+func (r *Registry) Get(name string) (Tool, bool) {
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Schemas returns the schema of every registered tool, in registration
+// order.
+func (r *Registry) Schemas() []Schema {
+	schemas := make([]Schema, 0, len(r.order))
+	for _, name := range r.order {
+		schemas = append(schemas, r.tools[name].Describe())
+	}
+	return schemas
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/summerwind/gptask/log"
+	"github.com/summerwind/gptask/sandbox"
+	"github.com/summerwind/gptask/search"
+	"github.com/summerwind/gptask/shell"
+	"github.com/summerwind/gptask/tool"
+)
+
+// fileTool writes content to a file on disk.
+type fileTool struct {
+	shell  *shell.Shell
+	policy *sandbox.Policy
+}
+
+func (t *fileTool) Name() string { return "file" }
+
+func (t *fileTool) Describe() tool.Schema {
+	return tool.Schema{
+		Name:        "file",
+		Description: "Write content to a file.",
+		Input:       `YAML with "path" and "content" fields`,
+	}
+}
+
+func (t *fileTool) Run(ctx context.Context, input string) (string, error) {
+	var fileInput FileActionInput
+
+	err := yaml.Unmarshal([]byte(input), &fileInput)
+	if err != nil {
+		return "", err
+	}
+
+	log.Command(fmt.Sprintf("vim %s", fileInput.Path))
+
+	if fileInput.Path == "" {
+		output := "file path must be specified"
+		log.Stderr(output)
+		return output, nil
+	}
+
+	if !filepath.IsAbs(fileInput.Path) {
+		fileInput.Path = filepath.Join(t.shell.WorkDir(), fileInput.Path)
+	}
+
+	if t.policy != nil {
+		if err := t.policy.CheckWrite(fileInput.Path, t.shell.WorkDir()); err != nil {
+			log.Stderr(err.Error())
+			return err.Error(), nil
+		}
+	}
+
+	dirPath := filepath.Dir(fileInput.Path)
+	err = os.MkdirAll(dirPath, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(fileInput.Path, []byte(fileInput.Content), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	log.CodeBlock(fileInput.Content)
+
+	return ObservationSuccess, nil
+}
+
+// pythonTool runs a Python 3 script and returns its output.
+type pythonTool struct {
+	shell  *shell.Shell
+	policy *sandbox.Policy
+}
+
+func (t *pythonTool) Name() string { return "python" }
+
+func (t *pythonTool) Describe() tool.Schema {
+	return tool.Schema{
+		Name:        "python",
+		Description: "Run Python 3 code.",
+		Input:       "the Python code to run",
+	}
+}
+
+func (t *pythonTool) Run(ctx context.Context, input string) (string, error) {
+	if t.policy != nil {
+		if err := t.policy.CheckShell(input); err != nil {
+			log.Stderr(err.Error())
+			return err.Error(), nil
+		}
+		if err := t.policy.CheckNetwork(input); err != nil {
+			log.Stderr(err.Error())
+			return err.Error(), nil
+		}
+	}
+
+	log.Command("python3")
+	log.CodeBlock(input)
+
+	workDir := t.shell.WorkDir()
+
+	name, args := "python3", []string{"-c", input}
+	if t.policy != nil {
+		name, args = t.policy.Wrap(name, args, workDir)
+	}
+
+	python := exec.CommandContext(ctx, name, args...)
+	if t.policy == nil || t.policy.Isolation == sandbox.IsolationNone || t.policy.Isolation == "" {
+		python.Dir = workDir
+	}
+
+	output, err := python.CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			log.Stderr(string(output))
+			return string(output), nil
+		}
+		return "", err
+	}
+
+	outputStr := strings.TrimRight(string(output), "\n")
+	if outputStr == "" {
+		outputStr = ObservationSuccessWithNoOutput
+	}
+
+	log.Stdout(outputStr)
+
+	return outputStr, nil
+}
+
+// shellTool runs one or more shell commands in the runner's persistent
+// shell and returns their output.
+type shellTool struct {
+	shell  *shell.Shell
+	policy *sandbox.Policy
+}
+
+func (t *shellTool) Name() string { return "shell" }
+
+func (t *shellTool) Describe() tool.Schema {
+	return tool.Schema{
+		Name:        "shell",
+		Description: "Run shell commands.",
+		Input:       "one shell command per line",
+	}
+}
+
+func (t *shellTool) Run(ctx context.Context, input string) (string, error) {
+	var (
+		rc     int
+		stdout string
+		stderr string
+		err    error
+	)
+
+	commands := strings.Split(input, "\n")
+
+	var progress *log.Progress
+	if len(commands) > 1 {
+		progress = log.NewProgress("shell", len(commands))
+		defer progress.Stop()
+	}
+
+	for i, cmd := range commands {
+		if progress != nil {
+			progress.Step(i + 1)
+		}
+
+		if t.policy != nil {
+			if err := t.policy.CheckShell(cmd); err != nil {
+				log.Stderr(err.Error())
+				return err.Error(), nil
+			}
+			if err := t.policy.CheckNetwork(cmd); err != nil {
+				log.Stderr(err.Error())
+				return err.Error(), nil
+			}
+		}
+
+		log.Command(cmd)
+
+		rc, stdout, stderr, err = t.shell.Run(cmd)
+		if err != nil {
+			return "", err
+		}
+
+		if rc != 0 {
+			if len(stderr) == 0 {
+				return fmt.Sprintf("failed (exit code: %d)", rc), nil
+			}
+			return stderr, nil
+		}
+	}
+
+	if len(stdout) == 0 {
+		return ObservationSuccessWithNoOutput, nil
+	}
+
+	return stdout, nil
+}
+
+// fetchPageLimit bounds how many top results have their page content
+// fetched when fetchPage is enabled, so one search doesn't turn into a
+// dozen page fetches.
+const fetchPageLimit = 2
+
+// searchTool searches the web through a pluggable search.Provider,
+// caching results on disk and optionally fetching the top results'
+// pages for the model to read directly.
+type searchTool struct {
+	provider    search.Provider
+	backendName string
+	cache       *search.Cache
+	fetchPage   bool
+}
+
+func (t *searchTool) Name() string { return "search" }
+
+func (t *searchTool) Describe() tool.Schema {
+	return tool.Schema{
+		Name:        "search",
+		Description: "Search the web.",
+		Input:       "the search query",
+	}
+}
+
+func (t *searchTool) Run(ctx context.Context, input string) (string, error) {
+	log.Command(fmt.Sprintf("search %s", input))
+
+	if input == "" {
+		output := "query must be specified"
+		log.Stderr(output)
+		return output, nil
+	}
+
+	results, cached := t.cache.Get(t.backendName, input)
+	if !cached {
+		r, err := t.provider.Search(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		results = r
+
+		if err := t.cache.Put(t.backendName, input, results); err != nil {
+			log.Debug("search-cache", err.Error())
+		}
+	}
+
+	if len(results) > 3 {
+		results = results[:3]
+	}
+
+	lines := []string{}
+	for i, r := range results {
+		lines = append(lines, fmt.Sprintf("%d. %s: %s", i+1, r.Title, r.Desc))
+
+		if t.fetchPage && i < fetchPageLimit {
+			text, err := search.FetchPage(ctx, r.URL)
+			if err != nil {
+				lines = append(lines, fmt.Sprintf("   (failed to fetch page: %s)", err))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("   page content: %s", truncate(text, 2000)))
+		}
+	}
+	output := strings.Join(lines, "\n")
+
+	log.CodeBlock(output)
+
+	return output, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}